@@ -0,0 +1,70 @@
+/*
+Copyright 2018 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ceph
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/cmd/rook/rook"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mgr"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var monitoringCmd = &cobra.Command{
+	Use:   "monitoring",
+	Short: "Manages the monitoring resources for a Ceph cluster",
+}
+
+var applyRulesCmd = &cobra.Command{
+	Use:   "apply-rules",
+	Short: "Creates or updates the PrometheusRule for a CephCluster outside of the cluster reconcile loop",
+	RunE:  applyMonitoringRules,
+}
+
+func init() {
+	monitoringCmd.AddCommand(applyRulesCmd)
+	CephCmd.AddCommand(monitoringCmd)
+}
+
+func applyMonitoringRules(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	clusterdContext := rook.NewContext()
+
+	namespace := k8sutil.GetOperatorNamespace()
+	cephCluster, err := clusterdContext.RookClientset.CephV1().CephClusters(namespace).Get(ctx, clusterName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get CephCluster %q", clusterName)
+	}
+
+	clusterInfo := cephclient.AdminClusterInfo(ctx, cephCluster.Namespace, cephCluster.Name)
+	c := mgr.New(clusterdContext, clusterInfo, cephCluster.Spec, opcontroller.LookupImageSpec(cephCluster))
+
+	// This command is the out-of-band path users reach for when
+	// spec.monitoring.createPrometheusRules has disabled the mgr-integrated path,
+	// so it calls ApplyPrometheusRule directly rather than CreateOrUpdatePrometheusRule.
+	if _, err := c.ApplyPrometheusRule(); err != nil {
+		return errors.Wrap(err, "failed to apply prometheus rule")
+	}
+
+	logger.Infof("successfully applied prometheus rule for CephCluster %q", clusterName)
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2018 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ceph
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/cmd/rook/rook"
+	"github.com/rook/rook/pkg/operator/ceph"
+	"github.com/spf13/cobra"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+var operatorCmd = &cobra.Command{
+	Use:   "operator",
+	Short: "Runs the Ceph operator for orchestrating a Ceph storage cluster",
+	RunE:  startOperator,
+}
+
+func init() {
+	CephCmd.AddCommand(operatorCmd)
+}
+
+func startOperator(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	clusterdContext := rook.NewContext()
+
+	cfg, err := ctrlconfig.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get kubernetes config")
+	}
+
+	mgr, err := manager.New(cfg, manager.Options{})
+	if err != nil {
+		return errors.Wrap(err, "failed to create controller-runtime manager")
+	}
+
+	if err := ceph.AddToManager(mgr, clusterdContext, ctx); err != nil {
+		return errors.Wrap(err, "failed to add Ceph controllers to manager")
+	}
+
+	logger.Info("starting ceph operator")
+	if err := mgr.Start(ctx); err != nil {
+		return errors.Wrap(err, "controller-runtime manager exited with an error")
+	}
+
+	return nil
+}
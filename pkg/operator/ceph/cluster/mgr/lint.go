@@ -0,0 +1,83 @@
+/*
+Copyright 2018 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"fmt"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mgr/lint"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const mgrConfigLintConditionType cephv1.ConditionType = "MgrConfigLinted"
+
+// lintGeneratedObjects runs the built-in sanitizer checks (see the lint
+// package) against the objects the mgr reconciler just generated, logs any
+// findings, and surfaces them as a condition on the CephCluster status so
+// misconfigurations are visible at reconcile time instead of at scrape or
+// probe failure time.
+func (c *Cluster) lintGeneratedObjects(deployment *apps.Deployment, metricsService, dashboardService *v1.Service) {
+	var nodes []v1.Node
+	nodeList, err := c.context.Clientset.CoreV1().Nodes().List(c.clusterInfo.Context, metav1.ListOptions{})
+	if err != nil {
+		logger.Warningf("failed to list nodes for mgr anti-affinity lint check, skipping that check: %v", err)
+	} else {
+		nodes = nodeList.Items
+	}
+
+	report := lint.Run(lint.Input{
+		Spec:             &c.spec,
+		Deployment:       deployment,
+		MetricsService:   metricsService,
+		DashboardService: dashboardService,
+		Nodes:            nodes,
+	})
+
+	for _, issue := range report.Issues {
+		logger.Warningf("mgr lint: %s", issue.String())
+	}
+
+	status := v1.ConditionTrue
+	message := "no issues found linting generated mgr objects"
+	if report.HasErrors() {
+		status = v1.ConditionFalse
+	}
+	if len(report.Issues) > 0 {
+		message = fmt.Sprintf("%d issue(s) found linting generated mgr objects, see operator logs for details", len(report.Issues))
+	}
+
+	condition := cephv1.Condition{
+		Type:    mgrConfigLintConditionType,
+		Status:  status,
+		Reason:  "MgrObjectLintCompleted",
+		Message: message,
+	}
+
+	cephCluster, err := c.context.RookClientset.CephV1().CephClusters(c.clusterInfo.Namespace).Get(c.clusterInfo.Context, c.clusterInfo.NamespacedName().Name, metav1.GetOptions{})
+	if err != nil {
+		logger.Warningf("failed to get CephCluster to record mgr lint condition: %v", err)
+		return
+	}
+
+	cephv1.SetCondition(&cephCluster.Status, condition)
+	if _, err := c.context.RookClientset.CephV1().CephClusters(c.clusterInfo.Namespace).UpdateStatus(c.clusterInfo.Context, cephCluster, metav1.UpdateOptions{}); err != nil {
+		logger.Warningf("failed to update CephCluster status with mgr lint condition: %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2018 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lint
+
+import (
+	"strconv"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	mgrContainerName     = "mgr"
+	sidecarContainerName = "watch-active"
+
+	// defaultLivenessInitialDelaySeconds mirrors getDefaultMgrLivenessProbe in
+	// the mgr package.
+	defaultLivenessInitialDelaySeconds = 60
+)
+
+// checkContainerResources flags mgr/watch-active containers that have no
+// resource requests or limits set, which leaves them unbounded on the node.
+func checkContainerResources(in Input, report *Report) {
+	if in.Deployment == nil {
+		return
+	}
+
+	for _, container := range in.Deployment.Spec.Template.Spec.Containers {
+		if container.Name != mgrContainerName && container.Name != sidecarContainerName {
+			continue
+		}
+		if len(container.Resources.Requests) == 0 && len(container.Resources.Limits) == 0 {
+			report.add("container-resources", SeverityWarning,
+				"container %q has no resource requests or limits set", container.Name)
+		}
+	}
+}
+
+// checkLivenessProbe flags a mgr container still running with the default
+// liveness probe when the CephCluster CR customizes the mgr health check,
+// which usually means the customization silently failed to apply.
+func checkLivenessProbe(in Input, report *Report) {
+	if in.Deployment == nil || in.Spec == nil {
+		return
+	}
+
+	customProbe, ok := in.Spec.HealthCheck.LivenessProbe[cephv1.KeyMgr]
+	if !ok || customProbe == nil || customProbe.Probe == nil {
+		return
+	}
+
+	for _, container := range in.Deployment.Spec.Template.Spec.Containers {
+		if container.Name != mgrContainerName {
+			continue
+		}
+		if container.LivenessProbe == nil {
+			report.add("liveness-probe", SeverityError, "mgr container has no liveness probe despite a custom healthCheck being set")
+			return
+		}
+		if container.LivenessProbe.InitialDelaySeconds == defaultLivenessInitialDelaySeconds &&
+			customProbe.Probe.InitialDelaySeconds != 0 &&
+			customProbe.Probe.InitialDelaySeconds != defaultLivenessInitialDelaySeconds {
+			report.add("liveness-probe", SeverityWarning, "mgr container liveness probe still uses the default InitialDelaySeconds despite a custom healthCheck being set")
+		}
+	}
+}
+
+// checkDeploymentStrategy flags a Recreate deployment strategy when more than
+// one mgr replica is requested, which would cause an unnecessary outage on
+// every rollout since Recreate tears down all replicas before starting new ones.
+func checkDeploymentStrategy(in Input, report *Report) {
+	if in.Deployment == nil || in.Spec == nil {
+		return
+	}
+	if in.Spec.Mgr.Count > 1 && in.Deployment.Spec.Strategy.Type == apps.RecreateDeploymentStrategyType {
+		report.add("deployment-strategy", SeverityWarning,
+			"mgr deployment uses RecreateDeploymentStrategyType with Mgr.Count=%d; rollouts will take down all mgr replicas at once", in.Spec.Mgr.Count)
+	}
+}
+
+// checkAntiAffinityTopology flags an anti-affinity topology key that doesn't
+// resolve against any label actually present on the cluster's nodes, which
+// means the anti-affinity rule is silently a no-op.
+func checkAntiAffinityTopology(in Input, report *Report) {
+	if in.Deployment == nil || len(in.Nodes) == 0 {
+		return
+	}
+
+	affinity := in.Deployment.Spec.Template.Spec.Affinity
+	if affinity == nil || affinity.PodAntiAffinity == nil {
+		return
+	}
+
+	for _, key := range topologyKeys(affinity.PodAntiAffinity) {
+		if !anyNodeHasLabel(in.Nodes, key) {
+			report.add("anti-affinity-topology", SeverityWarning,
+				"anti-affinity topology key %q does not match any label present on cluster nodes", key)
+		}
+	}
+}
+
+func topologyKeys(podAntiAffinity *v1.PodAntiAffinity) []string {
+	var keys []string
+	for _, term := range podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+		keys = append(keys, term.TopologyKey)
+	}
+	for _, term := range podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		keys = append(keys, term.PodAffinityTerm.TopologyKey)
+	}
+	return keys
+}
+
+func anyNodeHasLabel(nodes []v1.Node, key string) bool {
+	for _, node := range nodes {
+		if _, ok := node.Labels[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// checkMetricsAnnotation flags a prometheus.io/port annotation that doesn't
+// match the container port actually exposed for http-metrics, which would
+// leave a non-Prometheus-Operator scrape config pointed at the wrong port.
+func checkMetricsAnnotation(in Input, report *Report) {
+	if in.Deployment == nil {
+		return
+	}
+
+	annotation, ok := in.Deployment.Spec.Template.Annotations["prometheus.io/port"]
+	if !ok {
+		return
+	}
+
+	for _, container := range in.Deployment.Spec.Template.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.Name != "http-metrics" {
+				continue
+			}
+			if annotation != portToString(port.ContainerPort) {
+				report.add("metrics-annotation", SeverityError,
+					"prometheus.io/port annotation %q does not match the exposed http-metrics container port %d", annotation, port.ContainerPort)
+			}
+			return
+		}
+	}
+}
+
+func portToString(port int32) string {
+	return strconv.Itoa(int(port))
+}
+
+// checkDashboardPortName flags a dashboard Service whose port name doesn't
+// match spec.Dashboard.SSL, which otherwise silently breaks TLS-aware
+// consumers of the service (e.g. Routes/Ingresses keyed off the port name).
+func checkDashboardPortName(in Input, report *Report) {
+	if in.DashboardService == nil || in.Spec == nil {
+		return
+	}
+
+	want := "http-dashboard"
+	if in.Spec.Dashboard.SSL {
+		want = "https-dashboard"
+	}
+
+	for _, port := range in.DashboardService.Spec.Ports {
+		if port.Name != want {
+			report.add("dashboard-port-name", SeverityError,
+				"dashboard service port is named %q but spec.Dashboard.SSL=%t expects %q", port.Name, in.Spec.Dashboard.SSL, want)
+		}
+	}
+}
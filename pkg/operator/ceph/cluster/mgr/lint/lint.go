@@ -0,0 +1,111 @@
+/*
+Copyright 2018 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lint runs a small set of Popeye-style sanitizer checks against the
+// Kubernetes objects the mgr package generates (Deployment, metrics Service,
+// dashboard Service) before they're applied, so misconfigurations are caught
+// at reconcile time rather than at scrape or probe failure time.
+package lint
+
+import (
+	"fmt"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Severity is how serious a finding is.
+type Severity string
+
+const (
+	// SeverityWarning flags a finding that should be looked at but doesn't
+	// block reconciliation.
+	SeverityWarning Severity = "Warning"
+	// SeverityError flags a finding that is very likely to cause a runtime
+	// failure (failed probes, missing metrics, etc).
+	SeverityError Severity = "Error"
+)
+
+// Issue is a single finding produced by a Check.
+type Issue struct {
+	Check    string
+	Severity Severity
+	Message  string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("[%s] %s: %s", i.Severity, i.Check, i.Message)
+}
+
+// Report is the accumulated result of running the linter against a set of
+// generated objects.
+type Report struct {
+	Issues []Issue
+}
+
+// HasErrors returns true if the report contains at least one SeverityError issue.
+func (r *Report) HasErrors() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Report) add(check string, severity Severity, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, Issue{
+		Check:    check,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Input bundles the objects and cluster context a mgr lint pass needs. Nodes
+// is used to validate anti-affinity topology keys against real node labels;
+// it can be left nil to skip that check (e.g. when node listing isn't
+// available to the caller).
+type Input struct {
+	Spec             *cephv1.ClusterSpec
+	Deployment       *apps.Deployment
+	MetricsService   *v1.Service
+	DashboardService *v1.Service
+	Nodes            []v1.Node
+}
+
+// Run executes every registered check against the given input and returns the
+// accumulated report. A nil Deployment/Service is simply skipped by the checks
+// that depend on it.
+func Run(in Input) *Report {
+	report := &Report{}
+	for _, check := range checks {
+		check(in, report)
+	}
+	return report
+}
+
+// checkFunc is the shape every sanitizer check must implement.
+type checkFunc func(Input, *Report)
+
+var checks = []checkFunc{
+	checkContainerResources,
+	checkLivenessProbe,
+	checkDeploymentStrategy,
+	checkAntiAffinityTopology,
+	checkMetricsAnnotation,
+	checkDashboardPortName,
+}
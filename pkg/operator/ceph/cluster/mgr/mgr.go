@@ -0,0 +1,131 @@
+/*
+Copyright 2018 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"fmt"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+)
+
+const (
+	// AppName is the name of the mgr app and the base name used for the mgr's
+	// Kubernetes resources.
+	AppName = "rook-ceph-mgr"
+
+	serviceAccountName = "rook-ceph-mgr"
+
+	// DefaultMetricsPort is the port the mgr Prometheus module listens on.
+	DefaultMetricsPort = 9283
+
+	dashboardPortHTTP  = 7000
+	dashboardPortHTTPS = 8443
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", "cephmgr")
+
+// Cluster holds the context and CR spec needed to reconcile the Ceph mgr
+// daemon(s) for a CephCluster.
+type Cluster struct {
+	context     *clusterd.Context
+	clusterInfo *cephclient.ClusterInfo
+	spec        cephv1.ClusterSpec
+	rookVersion string
+}
+
+// mgrConfig holds the per-mgr-daemon identity used to render its Deployment.
+type mgrConfig struct {
+	DaemonID     string
+	ResourceName string
+	DataPathMap  *config.DataPathMap
+}
+
+// New returns a Cluster that can reconcile the mgr daemon(s) for the given
+// CephCluster.
+func New(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, spec cephv1.ClusterSpec, rookVersion string) *Cluster {
+	return &Cluster{
+		context:     context,
+		clusterInfo: clusterInfo,
+		spec:        spec,
+		rookVersion: rookVersion,
+	}
+}
+
+// dashboardPort returns the port the dashboard module listens on, depending
+// on whether spec.dashboard.ssl is enabled.
+func (c *Cluster) dashboardPort() int {
+	if c.spec.Dashboard.SSL {
+		return dashboardPortHTTPS
+	}
+	return dashboardPortHTTP
+}
+
+// Start generates the mgr Deployment and the metrics and dashboard Services,
+// lints them, creates or updates the Deployment, the metrics Service and -
+// when spec.dashboard.enabled - the dashboard Service, and - when
+// spec.monitoring.enabled - reconciles the ServiceMonitor and PrometheusRule
+// for the cluster. Only the Deployment for the first mgr daemon is created.
+func (c *Cluster) Start() error {
+	activeDaemon := k8sutil.IndexToName(0)
+
+	mgrConfig := &mgrConfig{
+		DaemonID:     activeDaemon,
+		ResourceName: fmt.Sprintf("%s-%s", AppName, activeDaemon),
+		DataPathMap:  config.NewStatelessDaemonDataPathMap(config.MgrType, activeDaemon, c.clusterInfo.Namespace, c.spec.DataDirHostPath),
+	}
+
+	d, err := c.makeDeployment(mgrConfig)
+	if err != nil {
+		return errors.Wrapf(err, "failed to generate mgr deployment %q", mgrConfig.ResourceName)
+	}
+
+	metricsService, err := c.MakeMetricsService(AppName, activeDaemon, serviceMetricName)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate mgr metrics service")
+	}
+
+	dashboardService, err := c.makeDashboardService(AppName, activeDaemon)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate mgr dashboard service")
+	}
+
+	c.lintGeneratedObjects(d, metricsService, dashboardService)
+
+	if _, err := k8sutil.CreateOrUpdateDeployment(c.clusterInfo.Context, c.context.Clientset, d); err != nil {
+		return errors.Wrapf(err, "failed to create or update mgr deployment %q", mgrConfig.ResourceName)
+	}
+	if _, err := k8sutil.CreateOrUpdateService(c.clusterInfo.Context, c.context.Clientset, c.clusterInfo.Namespace, metricsService); err != nil {
+		return errors.Wrap(err, "failed to create or update mgr metrics service")
+	}
+	if c.spec.Dashboard.Enabled {
+		if _, err := k8sutil.CreateOrUpdateService(c.clusterInfo.Context, c.context.Clientset, c.clusterInfo.Namespace, dashboardService); err != nil {
+			return errors.Wrap(err, "failed to create or update mgr dashboard service")
+		}
+	}
+
+	if err := c.reconcileMonitoring(AppName, activeDaemon); err != nil {
+		return errors.Wrap(err, "failed to reconcile mgr monitoring objects")
+	}
+
+	return nil
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2018 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"bytes"
+	"reflect"
+	"text/template"
+
+	"github.com/pkg/errors"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// createPrometheusRulesEnabled reports whether spec.monitoring.createPrometheusRules
+// is enabled. The field defaults to creating rules when left unset, so that
+// existing clusters upgrading onto this CRD field keep getting their
+// PrometheusRule instead of having it silently disabled.
+func createPrometheusRulesEnabled(spec cephv1.ClusterSpec) bool {
+	if spec.Monitoring.CreatePrometheusRules == nil {
+		return true
+	}
+	return *spec.Monitoring.CreatePrometheusRules
+}
+
+// defaultPrometheusRuleCustomized returns the thresholds Rook has historically
+// shipped as fixed values in the PrometheusRule templates. A CephCluster CR
+// can override any of these via spec.monitoring.alerts.
+func defaultPrometheusRuleCustomized() *PrometheusRuleCustomized {
+	return &PrometheusRuleCustomized{
+		Labels: Labels{
+			Prometheus: "rook-prometheus",
+			Role:       "alert-rules",
+		},
+		Alerts: Alerts{
+			CephMgrIsAbsent:                  CephMgrIsAbsent{For: "5m", Severity: "critical", SeverityLevel: "error"},
+			CephMgrIsMissingReplicas:         CephMgrIsMissingReplicas{For: "5m", Severity: "warning", SeverityLevel: "warning"},
+			CephMdsMissingReplicas:           CephMdsMissingReplicas{For: "5m", Severity: "warning", SeverityLevel: "warning"},
+			CephMonQuorumAtRisk:              CephMonQuorumAtRisk{For: "5m", Severity: "critical", SeverityLevel: "error"},
+			CephMonQuorumLost:                CephMonQuorumLost{For: "0m", Severity: "critical", SeverityLevel: "error"},
+			CephMonHighNumberOfLeaderChanges: CephMonHighNumberOfLeaderChanges{Limit: 1, For: "15m", Severity: "warning", SeverityLevel: "warning"},
+			CephNodeDown:                     CephNodeDown{For: "10m", Severity: "warning", SeverityLevel: "warning"},
+			CephOSDCriticallyFull:            CephOSDCriticallyFull{Limit: 0.95, For: "1m", Severity: "critical", SeverityLevel: "error"},
+			CephOSDFlapping:                  CephOSDFlapping{Limit: 5, OsdUpRate: "5m", For: "5m", Severity: "warning", SeverityLevel: "warning"},
+			CephOSDNearFull:                  CephOSDNearFull{Limit: 0.85, For: "5m", Severity: "warning", SeverityLevel: "warning"},
+			CephOSDDiskNotResponding:         CephOSDDiskNotResponding{For: "5m", Severity: "critical", SeverityLevel: "error"},
+			CephOSDDiskUnavailable:           CephOSDDiskUnavailable{For: "1m", Severity: "critical", SeverityLevel: "error"},
+			CephOSDSlowOps:                   CephOSDSlowOps{For: "30s", Severity: "warning", SeverityLevel: "warning"},
+			CephDataRecoveryTakingTooLong:    CephDataRecoveryTakingTooLong{For: "2h", Severity: "warning", SeverityLevel: "warning"},
+			CephPGRepairTakingTooLong:        CephPGRepairTakingTooLong{For: "0m", Severity: "warning", SeverityLevel: "warning"},
+			PersistentVolumeUsageNearFull:    PersistentVolumeUsageNearFull{Limit: 0.75, For: "5m", Severity: "warning", SeverityLevel: "warning"},
+			PersistentVolumeUsageCritical:    PersistentVolumeUsageCritical{Limit: 0.85, For: "5m", Severity: "critical", SeverityLevel: "error"},
+			CephClusterErrorState:            CephClusterErrorState{For: "10m", Severity: "critical", SeverityLevel: "error"},
+			CephClusterWarningState:          CephClusterWarningState{For: "15m", Severity: "warning", SeverityLevel: "warning"},
+			CephOSDVersionMismatch:           CephOSDVersionMismatch{For: "5m", Severity: "warning", SeverityLevel: "warning"},
+			CephMonVersionMismatch:           CephMonVersionMismatch{For: "5m", Severity: "warning", SeverityLevel: "warning"},
+			CephClusterNearFull:              CephClusterNearFull{Limit: 0.75, For: "5m", Severity: "warning", SeverityLevel: "warning"},
+			CephClusterCriticallyFull:        CephClusterCriticallyFull{Limit: 0.85, For: "1m", Severity: "critical", SeverityLevel: "error"},
+			CephClusterReadOnly:              CephClusterReadOnly{Limit: 0.95, For: "1m", Severity: "critical", SeverityLevel: "error"},
+			CephPoolQuotaBytesNearExhaustion: CephPoolQuotaBytesNearExhaustion{Limit: 0.70, For: "5m", Severity: "warning", SeverityLevel: "warning"},
+			CephPoolQuotaBytesCriticallyExhausted: CephPoolQuotaBytesCriticallyExhausted{
+				Limit: 0.90, For: "1m", Severity: "critical", SeverityLevel: "error",
+			},
+		},
+	}
+}
+
+// prometheusRuleCustomized builds the PrometheusRuleCustomized used to render the
+// embedded templates, starting from Rook's built-in defaults and applying any
+// per-alert overrides set on the CephCluster CR at spec.monitoring.alerts.
+func (c *Cluster) prometheusRuleCustomized() *PrometheusRuleCustomized {
+	customized := defaultPrometheusRuleCustomized()
+	customized.Labels.ManagedBy = c.managedByLabel()
+	customized.Labels.Namespace = c.clusterInfo.Namespace
+	if c.spec.Monitoring.Alerts != nil {
+		customized.merge(c.spec.Monitoring.Alerts)
+	}
+	return customized
+}
+
+// merge overlays any non-zero-value alert fields set in override onto p, leaving
+// Rook's defaults in place for everything the user didn't set. It walks every
+// field of every alert generically so that a new alert added to the Alerts
+// struct is automatically tunable from the CR without editing this function.
+func (p *PrometheusRuleCustomized) merge(override *PrometheusRuleCustomized) {
+	if override.Labels.Prometheus != "" {
+		p.Labels.Prometheus = override.Labels.Prometheus
+	}
+	if override.Labels.Role != "" {
+		p.Labels.Role = override.Labels.Role
+	}
+
+	mergeAlerts(&p.Alerts, &override.Alerts)
+}
+
+// mergeAlerts copies every non-zero-value field of every alert in src onto the
+// matching field in dst, using reflection so each alert's For/Severity/
+// SeverityLevel/Limit (and any alert-specific field such as OsdUpRate) is
+// covered without having to special-case each alert by name.
+func mergeAlerts(dst, src *Alerts) {
+	dstV := reflect.ValueOf(dst).Elem()
+	srcV := reflect.ValueOf(src).Elem()
+
+	for i := 0; i < dstV.NumField(); i++ {
+		mergeAlertFields(dstV.Field(i), srcV.Field(i))
+	}
+}
+
+func mergeAlertFields(dst, src reflect.Value) {
+	for i := 0; i < dst.NumField(); i++ {
+		srcField := src.Field(i)
+		if srcField.IsZero() {
+			continue
+		}
+		dst.Field(i).Set(srcField)
+	}
+}
+
+// generatePrometheusRule renders the given template against the cluster's
+// PrometheusRuleCustomized and unmarshals the result into a PrometheusRule object.
+func (c *Cluster) generatePrometheusRule(templateData string) (*monitoringv1.PrometheusRule, error) {
+	t, err := template.New("prometheusRule").Parse(templateData)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse prometheus rule template")
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, c.prometheusRuleCustomized()); err != nil {
+		return nil, errors.Wrap(err, "failed to render prometheus rule template")
+	}
+
+	var rule monitoringv1.PrometheusRule
+	if err := yaml.Unmarshal(buf.Bytes(), &rule); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal rendered prometheus rule")
+	}
+	rule.Namespace = c.clusterInfo.Namespace
+
+	return &rule, nil
+}
+
+// CreateOrUpdatePrometheusRule renders the PrometheusRule template appropriate for
+// this cluster (internal or external) and reconciles it against the cluster, unless
+// spec.monitoring.createPrometheusRules is disabled.
+//
+// This is the mgr-integrated path, called from reconcileMonitoring on every mgr
+// reconcile that has spec.monitoring.enabled set. Setting spec.monitoring.createPrometheusRules to false stops this path
+// from creating the rule so it can be reconciled out-of-band instead, e.g. via the
+// "rook ceph monitoring apply-rules" CLI or the standalone monitoring controller -
+// both of those call ApplyPrometheusRule directly, bypassing this gate.
+func (c *Cluster) CreateOrUpdatePrometheusRule() (*monitoringv1.PrometheusRule, error) {
+	if !createPrometheusRulesEnabled(c.spec) {
+		logger.Info("prometheus rule creation is disabled, skipping")
+		return nil, nil
+	}
+
+	return c.ApplyPrometheusRule()
+}
+
+// ApplyPrometheusRule renders the PrometheusRule template appropriate for this
+// cluster (internal or external) and reconciles it against the cluster,
+// unconditionally of spec.monitoring.createPrometheusRules. It's the primitive
+// the out-of-band callers (the apply-rules CLI and the standalone monitoring
+// controller) use, since their entire purpose is to create the rule when the
+// mgr-integrated path has been told not to.
+func (c *Cluster) ApplyPrometheusRule() (*monitoringv1.PrometheusRule, error) {
+	if !prometheusOperatorCRDsAvailable(c.context.Clientset.Discovery()) {
+		logger.Info("prometheus operator CRDs not found, skipping prometheus rule creation")
+		return nil, nil
+	}
+
+	templateData := PrometheusRuleTemplatePath
+	if c.spec.External.Enable {
+		templateData = PrometheusRuleExternalTemplatePath
+	}
+
+	rule, err := c.generatePrometheusRule(templateData)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate prometheus rule")
+	}
+
+	if err := c.clusterInfo.OwnerInfo.SetControllerReference(rule); err != nil {
+		return nil, errors.Wrapf(err, "failed to set owner reference to prometheus rule %q", rule.Name)
+	}
+
+	ruleClient := c.context.PrometheusOperatorClientset.MonitoringV1().PrometheusRules(c.clusterInfo.Namespace)
+	existing, err := ruleClient.Get(c.clusterInfo.Context, rule.Name, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to get existing prometheus rule %q", rule.Name)
+		}
+		created, err := ruleClient.Create(c.clusterInfo.Context, rule, metav1.CreateOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create prometheus rule %q", rule.Name)
+		}
+		return created, nil
+	}
+
+	rule.ResourceVersion = existing.ResourceVersion
+	updated, err := ruleClient.Update(c.clusterInfo.Context, rule, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update prometheus rule %q", rule.Name)
+	}
+	return updated, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2018 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"github.com/pkg/errors"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+)
+
+const (
+	monitoringGroupVersion = "monitoring.coreos.com/v1"
+)
+
+// prometheusOperatorCRDsAvailable checks whether the Prometheus Operator CRDs
+// (PrometheusRule, ServiceMonitor, ...) are registered on the cluster, so the
+// mgr reconciler can skip creating monitoring objects gracefully on clusters
+// where the Prometheus Operator isn't installed.
+func prometheusOperatorCRDsAvailable(discoveryClient discovery.DiscoveryInterface) bool {
+	_, err := discoveryClient.ServerResourcesForGroupVersion(monitoringGroupVersion)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			logger.Warningf("failed to detect prometheus operator CRDs, assuming absent: %v", err)
+		}
+		return false
+	}
+	return true
+}
+
+// CreateOrUpdateServiceMonitor renders the ServiceMonitor for the mgr metrics
+// service and reconciles it against the cluster. It is a no-op when the
+// Prometheus Operator CRDs are not registered.
+func (c *Cluster) CreateOrUpdateServiceMonitor(name, activeDaemon string) (*monitoringv1.ServiceMonitor, error) {
+	if !prometheusOperatorCRDsAvailable(c.context.Clientset.Discovery()) {
+		logger.Info("prometheus operator CRDs not found, skipping service monitor creation")
+		return nil, nil
+	}
+
+	sm, err := c.MakeServiceMonitor(name, activeDaemon)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate service monitor")
+	}
+
+	smClient := c.context.PrometheusOperatorClientset.MonitoringV1().ServiceMonitors(c.clusterInfo.Namespace)
+	existing, err := smClient.Get(c.clusterInfo.Context, sm.Name, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to get existing service monitor %q", sm.Name)
+		}
+		created, err := smClient.Create(c.clusterInfo.Context, sm, metav1.CreateOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create service monitor %q", sm.Name)
+		}
+		return created, nil
+	}
+
+	sm.ResourceVersion = existing.ResourceVersion
+	updated, err := smClient.Update(c.clusterInfo.Context, sm, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update service monitor %q", sm.Name)
+	}
+	return updated, nil
+}
+
+// reconcileMonitoring provisions the ServiceMonitor for the mgr metrics Service
+// and, unless spec.monitoring.createPrometheusRules is false, the
+// PrometheusRule when spec.monitoring.enabled is set. The metrics Service
+// itself is created by Start. It's called after the mgr deployment and
+// services are reconciled.
+func (c *Cluster) reconcileMonitoring(name, activeDaemon string) error {
+	if !c.spec.Monitoring.Enabled {
+		return nil
+	}
+
+	if _, err := c.CreateOrUpdateServiceMonitor(name, activeDaemon); err != nil {
+		return errors.Wrap(err, "failed to reconcile service monitor")
+	}
+
+	if _, err := c.CreateOrUpdatePrometheusRule(); err != nil {
+		return errors.Wrap(err, "failed to reconcile prometheus rule")
+	}
+
+	return nil
+}
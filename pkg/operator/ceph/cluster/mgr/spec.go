@@ -23,6 +23,7 @@ import (
 	"strconv"
 
 	"github.com/pkg/errors"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	rookv1 "github.com/rook/rook/pkg/apis/rook.io/v1"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
@@ -252,6 +253,7 @@ func getDefaultMgrLivenessProbe() *v1.Probe {
 // MakeMetricsService generates the Kubernetes service object for the monitoring service
 func (c *Cluster) MakeMetricsService(name, activeDaemon, servicePortMetricName string) (*v1.Service, error) {
 	labels := c.selectorLabels(activeDaemon)
+	labels["managed_by"] = c.managedByLabel()
 
 	svc := &v1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -283,6 +285,70 @@ func (c *Cluster) MakeMetricsService(name, activeDaemon, servicePortMetricName s
 	return svc, nil
 }
 
+// MakeServiceMonitor generates the ServiceMonitor that has Prometheus scrape the
+// mgr's http-metrics port exposed by the service created in MakeMetricsService.
+func (c *Cluster) MakeServiceMonitor(name, activeDaemon string) (*monitoringv1.ServiceMonitor, error) {
+	labels := c.selectorLabels(activeDaemon)
+	labels["managed_by"] = c.managedByLabel()
+
+	endpoint := monitoringv1.Endpoint{
+		Port:        serviceMetricName,
+		Path:        "/metrics",
+		Interval:    c.monitoringScrapeInterval(),
+		HonorLabels: c.spec.Monitoring.HonorLabels,
+		RelabelConfigs: []*monitoringv1.RelabelConfig{
+			{
+				Action:      "replace",
+				TargetLabel: "managed_by",
+				Replacement: c.managedByLabel(),
+			},
+		},
+	}
+
+	if c.spec.Monitoring.BearerTokenSecret != nil {
+		endpoint.BearerTokenSecret = *c.spec.Monitoring.BearerTokenSecret
+	}
+
+	if c.spec.Monitoring.TLSConfig != nil {
+		endpoint.TLSConfig = c.spec.Monitoring.TLSConfig
+	}
+
+	if len(c.spec.Monitoring.RelabelConfigs) > 0 {
+		endpoint.RelabelConfigs = append(endpoint.RelabelConfigs, c.spec.Monitoring.RelabelConfigs...)
+	}
+
+	sm := &monitoringv1.ServiceMonitor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.clusterInfo.Namespace,
+			Labels:    labels,
+		},
+		Spec: monitoringv1.ServiceMonitorSpec{
+			Selector: metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			NamespaceSelector: monitoringv1.NamespaceSelector{
+				MatchNames: []string{c.clusterInfo.Namespace},
+			},
+			Endpoints: []monitoringv1.Endpoint{endpoint},
+		},
+	}
+
+	if err := c.clusterInfo.OwnerInfo.SetControllerReference(sm); err != nil {
+		return nil, errors.Wrapf(err, "failed to set owner reference to service monitor %q", sm.Name)
+	}
+	return sm, nil
+}
+
+// monitoringScrapeInterval returns the CR-configured scrape interval, falling
+// back to the Prometheus Operator default of 30s when unset.
+func (c *Cluster) monitoringScrapeInterval() string {
+	if c.spec.Monitoring.Interval != nil && c.spec.Monitoring.Interval.Duration > 0 {
+		return c.spec.Monitoring.Interval.Duration.String()
+	}
+	return "30s"
+}
+
 func (c *Cluster) makeDashboardService(name, activeDaemon string) (*v1.Service, error) {
 	labels := c.selectorLabels(activeDaemon)
 
@@ -346,6 +412,18 @@ func (c *Cluster) cephMgrOrchestratorModuleEnvs() []v1.EnvVar {
 	return envVars
 }
 
+// managedByLabel returns the value used to tag this cluster's monitoring
+// objects (Service, ServiceMonitor, PrometheusRule) so a single Prometheus can
+// safely scrape and alert on multiple Rook-Ceph clusters without cross-talk.
+// It defaults to the CephCluster name and can be overridden via
+// spec.monitoring.alerts.labels.managedBy.
+func (c *Cluster) managedByLabel() string {
+	if c.spec.Monitoring.Alerts != nil && c.spec.Monitoring.Alerts.Labels.ManagedBy != "" {
+		return c.spec.Monitoring.Alerts.Labels.ManagedBy
+	}
+	return c.clusterInfo.NamespacedName().Name
+}
+
 func (c *Cluster) selectorLabels(activeDaemon string) map[string]string {
 	labels := controller.AppLabels(AppName, c.clusterInfo.Namespace)
 	if activeDaemon != "" {
@@ -361,6 +439,16 @@ type PrometheusRuleCustomized struct {
 type Labels struct {
 	Prometheus string `yaml:"prometheus"`
 	Role       string `yaml:"role"`
+	// ManagedBy is injected as an external label on the PrometheusRule (and the
+	// corresponding ServiceMonitor) so a single Prometheus instance can safely
+	// monitor multiple Rook-Ceph clusters and alertmanager can route by cluster.
+	ManagedBy string `yaml:"managedBy"`
+	// Namespace is the CephCluster's namespace. It is always set by Rook from
+	// the cluster it's rendering for (never user-overridable) and lets
+	// namespace-scoped alerts like CephMgrIsAbsent match only this cluster's
+	// targets even though the rule expression can't aggregate `absent()`
+	// results `by (namespace)`.
+	Namespace string `yaml:"-"`
 }
 type CephMgrIsAbsent struct {
 	For           string `yaml:"for"`
@@ -0,0 +1,104 @@
+/*
+Copyright 2018 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package monitoring reconciles the PrometheusRule for a CephCluster
+// independently of the main cluster controller, so that alert rule rollout
+// can be gated and paced separately from the rest of the reconcile loop.
+package monitoring
+
+import (
+	"context"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mgr"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const controllerName = "ceph-monitoring-controller"
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+// ReconcileMonitoring reconciles the PrometheusRule for a CephCluster on its own
+// cadence, decoupled from the main cluster reconciliation loop.
+type ReconcileMonitoring struct {
+	client           client.Client
+	context          *clusterd.Context
+	opManagerContext context.Context
+}
+
+// Add registers the monitoring controller with the manager, watching CephCluster
+// objects so rules are kept in sync whenever spec.monitoring changes.
+func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context) error {
+	r := &ReconcileMonitoring{
+		client:           mgr.GetClient(),
+		context:          context,
+		opManagerContext: opManagerContext,
+	}
+
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return errors.Wrap(err, "failed to create monitoring controller")
+	}
+
+	if err := c.Watch(&source.Kind{Type: &cephv1.CephCluster{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return errors.Wrap(err, "failed to watch CephCluster resources")
+	}
+
+	return nil
+}
+
+// Reconcile renders and applies the PrometheusRule for the CephCluster named in
+// request, unless spec.monitoring is disabled. Unlike the mgr-integrated path,
+// this runs regardless of spec.monitoring.createPrometheusRules, since this
+// controller is the out-of-band path users opt into by disabling that flag.
+func (r *ReconcileMonitoring) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	cephCluster := &cephv1.CephCluster{}
+	err := r.client.Get(r.opManagerContext, request.NamespacedName, cephCluster)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, errors.Wrapf(err, "failed to get CephCluster %q", request.NamespacedName)
+	}
+
+	if !cephCluster.Spec.Monitoring.Enabled {
+		logger.Debugf("monitoring disabled for CephCluster %q, skipping prometheus rule reconciliation", request.NamespacedName)
+		return reconcile.Result{}, nil
+	}
+
+	clusterInfo := cephclient.AdminClusterInfo(r.opManagerContext, cephCluster.Namespace, cephCluster.Name)
+	c := mgr.New(r.context, clusterInfo, cephCluster.Spec, opcontroller.LookupImageSpec(cephCluster))
+
+	// This controller exists precisely to reconcile the rule out-of-band when
+	// spec.monitoring.createPrometheusRules has disabled the mgr-integrated path,
+	// so it calls ApplyPrometheusRule directly rather than CreateOrUpdatePrometheusRule.
+	if _, err := c.ApplyPrometheusRule(); err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to reconcile prometheus rule")
+	}
+
+	return reconcile.Result{}, nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2018 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ceph wires the individual Ceph operator controllers into the
+// controller-runtime manager.
+package ceph
+
+import (
+	"context"
+
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/ceph/monitoring"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// AddToManagerFuncs is the list of Ceph controllers registered with the
+// operator's manager. Each Ceph subsystem that runs its own controller,
+// rather than being driven from the main cluster controller, appends its
+// Add func here.
+var AddToManagerFuncs = []func(manager.Manager, *clusterd.Context, context.Context) error{
+	monitoring.Add,
+}
+
+// AddToManager registers every controller in AddToManagerFuncs with mgr.
+func AddToManager(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context) error {
+	for _, f := range AddToManagerFuncs {
+		if err := f(mgr, context, opManagerContext); err != nil {
+			return err
+		}
+	}
+	return nil
+}